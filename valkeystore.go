@@ -2,12 +2,18 @@ package valkeystore
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/base32"
 	"encoding/gob"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gorilla/securecookie"
 	"github.com/gorilla/sessions"
@@ -15,17 +21,125 @@ import (
 )
 
 const (
-	sessionExpire   = 3600 * 24 * 30 // 30 days
-	maxLength       = 4096           // Max length of a session in bytes.
-	randomKeyLength = 32             // Length of random key to generate if none exists.
+	sessionExpire    = 3600 * 24 * 30 // 30 days
+	defaultMaxLength = 4096           // Default max length of a single valkey value used for a session.
+	randomKeyLength  = 32             // Length of random key to generate if none exists.
+
+	chunkPartSuffix  = ":part:" // Suffix, followed by an index, for chunked session parts.
+	chunkCountSuffix = ":parts" // Suffix for the key holding the number of chunks, if any.
+	gzipMagic1       = 0x1f     // First byte of the gzip magic number.
+	gzipMagic2       = 0x8b     // Second byte of the gzip magic number.
 )
 
+// CompressionType selects the compression algorithm applied to a session's
+// serialized payload before it is written to valkey.
+type CompressionType int
+
+const (
+	// CompressionNone stores the serialized payload as-is.
+	CompressionNone CompressionType = iota
+	// CompressionGzip compresses the serialized payload with gzip.
+	CompressionGzip
+)
+
+// SessionSerializer provides an interface for encode/decode session values to/from
+// a valkey-storable representation. Implementations are responsible for producing
+// a self-contained byte slice that round-trips through Deserialize.
+type SessionSerializer interface {
+	// Serialize encodes the session's values into a byte slice.
+	Serialize(session *sessions.Session) ([]byte, error)
+	// Deserialize decodes data into the session's values.
+	Deserialize(data []byte, session *sessions.Session) error
+}
+
+// GobSerializer encodes session values with encoding/gob. This is the original,
+// default behavior of ValkeyStore: session values must be gob-registered types
+// and the stored payload is opaque outside of Go.
+type GobSerializer struct{}
+
+// Serialize encodes session.Values with gob.
+func (s GobSerializer) Serialize(session *sessions.Session) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	if err := gob.NewEncoder(buffer).Encode(session.Values); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Deserialize decodes data into session.Values with gob.
+func (s GobSerializer) Deserialize(data []byte, session *sessions.Session) error {
+	return gob.NewDecoder(bytes.NewBuffer(data)).Decode(&session.Values)
+}
+
+// JSONSerializer encodes session values as JSON, keeping the stored payload
+// readable from tools like valkey-cli and interoperable with non-Go services
+// sharing the same store. Because JSON object keys must be strings,
+// session.Values may only contain string keys; any other key type is rejected.
+type JSONSerializer struct{}
+
+// Serialize encodes session.Values as JSON.
+//
+// session.Values is a map[interface{}]interface{}, which does not round-trip
+// to JSON. Every key must be a string, otherwise an error is returned.
+func (s JSONSerializer) Serialize(session *sessions.Session) ([]byte, error) {
+	values := make(map[string]interface{}, len(session.Values))
+	for key, value := range session.Values {
+		stringKey, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("sessionstore: JSONSerializer requires string keys, got %T", key)
+		}
+
+		values[stringKey] = value
+	}
+
+	return json.Marshal(values)
+}
+
+// Deserialize decodes data into session.Values as map[string]interface{}.
+func (s JSONSerializer) Deserialize(data []byte, session *sessions.Session) error {
+	values := make(map[string]interface{})
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	session.Values = make(map[interface{}]interface{}, len(values))
+	for key, value := range values {
+		session.Values[key] = value
+	}
+
+	return nil
+}
+
 // ValkeyStore represents a valkey session store.
 type ValkeyStore struct {
-	Codecs    []securecookie.Codec
-	Options   *sessions.Options // default configuration
-	keyPrefix string
-	client    valkey.Client
+	Codecs      []securecookie.Codec
+	Options     *sessions.Options // default configuration
+	keyPrefix   string
+	client      valkey.Client
+	serializer  SessionSerializer
+	cacheTTL    time.Duration
+	maxLength   int
+	compression CompressionType
+	mu          sync.RWMutex // guards Codecs across SetMaxAge, RotateKeys, New and Save
+}
+
+// cacheDisabledKey is the context key used by WithoutCache to force load to
+// bypass client-side caching and read authoritative data from valkey.
+type cacheDisabledKey struct{}
+
+// WithoutCache returns a context derived from ctx that forces load to skip
+// the client-side cache, even when SetClientSideCache is enabled. Use this
+// for admin flows that must always see authoritative session data.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheDisabledKey{}, true)
+}
+
+// useCache reports whether load should route a single-key read through
+// DoCache, given the store's configured client-side cache TTL and whether
+// ctx was derived from WithoutCache.
+func useCache(ctx context.Context, cacheTTL time.Duration) bool {
+	return cacheTTL > 0 && ctx.Value(cacheDisabledKey{}) == nil
 }
 
 // New creates a new valkey store with the given parameters and key pairs.
@@ -64,6 +178,77 @@ func NewWithURL(url string, keyPairs ...[]byte) (*ValkeyStore, error) {
 	return NewWithClient(client, keyPairs...)
 }
 
+// ClusterOptions configures NewClusterStore.
+type ClusterOptions struct {
+	Username string
+	Password string
+	// ReadFromReplicas routes read-only commands, including session loads, to
+	// replica nodes, since reads dominate session traffic. Save and erase
+	// always go to primaries.
+	ReadFromReplicas bool
+}
+
+// NewClusterStore creates a new valkey store backed by a Valkey Cluster.
+// Session IDs are random base32, so they hash-slot evenly across the cluster.
+func NewClusterStore(addrs []string, opts ClusterOptions, keyPairs ...[]byte) (*ValkeyStore, error) {
+	option := valkey.ClientOption{
+		InitAddress: addrs,
+		Username:    opts.Username,
+		Password:    opts.Password,
+		ShuffleInit: true,
+	}
+
+	if opts.ReadFromReplicas {
+		option.SendToReplicas = func(cmd valkey.Completed) bool {
+			return cmd.IsReadOnly()
+		}
+	}
+
+	client, err := valkey.NewClient(option)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithClient(client, keyPairs...)
+}
+
+// SentinelOptions configures NewSentinelStore.
+type SentinelOptions struct {
+	Username string
+	Password string
+	// ReadFromReplicas routes read-only commands, including session loads, to
+	// replica nodes, since reads dominate session traffic. Save and erase
+	// always go to the primary.
+	ReadFromReplicas bool
+}
+
+// NewSentinelStore creates a new valkey store backed by a Sentinel-monitored
+// primary/replica deployment, failing over to whichever node Sentinel
+// currently reports as master for masterName.
+func NewSentinelStore(masterName string, addrs []string, opts SentinelOptions, keyPairs ...[]byte) (*ValkeyStore, error) {
+	option := valkey.ClientOption{
+		InitAddress: addrs,
+		Username:    opts.Username,
+		Password:    opts.Password,
+		Sentinel: valkey.SentinelOption{
+			MasterSet: masterName,
+		},
+	}
+
+	if opts.ReadFromReplicas {
+		option.SendToReplicas = func(cmd valkey.Completed) bool {
+			return cmd.IsReadOnly()
+		}
+	}
+
+	client, err := valkey.NewClient(option)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithClient(client, keyPairs...)
+}
+
 // NewWithClient creates a new valkey store with the given client and key pairs.
 func NewWithClient(client valkey.Client, keyPairs ...[]byte) (*ValkeyStore, error) {
 	store := &ValkeyStore{
@@ -72,8 +257,10 @@ func NewWithClient(client valkey.Client, keyPairs ...[]byte) (*ValkeyStore, erro
 			Path:   "/",
 			MaxAge: sessionExpire,
 		},
-		keyPrefix: "session-",
-		client:    client,
+		keyPrefix:  "session-",
+		client:     client,
+		serializer: GobSerializer{},
+		maxLength:  defaultMaxLength,
 	}
 
 	return store, store.ping()
@@ -84,6 +271,46 @@ func (r *ValkeyStore) SetKeyPrefix(keyPrefix string) {
 	r.keyPrefix = keyPrefix
 }
 
+// SetSerializer sets the SessionSerializer used to encode/decode session
+// values. Defaults to GobSerializer, matching the store's original behavior.
+func (r *ValkeyStore) SetSerializer(serializer SessionSerializer) {
+	r.serializer = serializer
+}
+
+// SetClientSideCache enables valkey-go's server-assisted client-side caching
+// for session reads, with the given client-side TTL. Hot sessions are then
+// served from the local cache instead of round-tripping to valkey on every
+// load; invalidation on save/erase is handled automatically by valkey's
+// tracking protocol.
+//
+// Changing keyPrefix requires a full client restart to flush the local
+// cache, since cached keys are not re-evaluated against the new prefix.
+//
+// Pass ttl <= 0 to disable client-side caching (the default).
+func (r *ValkeyStore) SetClientSideCache(ttl time.Duration) {
+	r.cacheTTL = ttl
+}
+
+// SetMaxLength sets the maximum size, in bytes, of a single valkey value used
+// to store a session's (possibly compressed) payload. Payloads larger than
+// this are transparently split into chunks on save and reassembled on load,
+// rather than rejected. Pass n <= 0 to restore the default (4096 bytes).
+func (r *ValkeyStore) SetMaxLength(n int) {
+	if n <= 0 {
+		n = defaultMaxLength
+	}
+
+	r.maxLength = n
+}
+
+// SetCompression enables transparent compression of session payloads before
+// they are written to valkey. Compressed payloads are detected on read via
+// the gzip magic number, so values written before compression was enabled
+// keep decoding correctly.
+func (r *ValkeyStore) SetCompression(compression CompressionType) {
+	r.compression = compression
+}
+
 // SetMaxAge restricts the maximum age, in seconds, of the session record
 // both in database and a browser. This is to change session storage configuration.
 // If you want just to remove session use your session `age` object and change it's
@@ -98,6 +325,9 @@ func (r *ValkeyStore) SetKeyPrefix(keyPrefix string) {
 func (r *ValkeyStore) SetMaxAge(maxAge int) {
 	r.Options.MaxAge = maxAge
 
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	for i := range r.Codecs {
 		if cookie, ok := r.Codecs[i].(*securecookie.SecureCookie); ok {
 			cookie.MaxAge(maxAge)
@@ -127,9 +357,11 @@ func (r *ValkeyStore) New(request *http.Request, name string) (*sessions.Session
 
 	if c, errCookie := request.Cookie(name); errCookie == nil {
 		var ok bool
+		r.mu.RLock()
 		err = securecookie.DecodeMulti(name, c.Value, &session.ID, r.Codecs...)
+		r.mu.RUnlock()
 		if err == nil {
-			ok, err = r.load(session)
+			ok, err = r.load(request.Context(), session)
 			session.IsNew = err != nil || !ok // not new if no error and data available
 		}
 	}
@@ -141,7 +373,7 @@ func (r *ValkeyStore) New(request *http.Request, name string) (*sessions.Session
 func (r *ValkeyStore) Save(request *http.Request, writer http.ResponseWriter, session *sessions.Session) error {
 	// Marked for deletion.
 	if session.Options.MaxAge <= 0 {
-		if err := r.erase(session); err != nil {
+		if err := r.erase(request.Context(), session); err != nil {
 			return err
 		}
 
@@ -155,11 +387,13 @@ func (r *ValkeyStore) Save(request *http.Request, writer http.ResponseWriter, se
 			)
 		}
 
-		if err := r.save(session); err != nil {
+		if err := r.save(request.Context(), session); err != nil {
 			return err
 		}
 
+		r.mu.RLock()
 		encoded, err := securecookie.EncodeMulti(session.Name(), session.ID, r.Codecs...)
+		r.mu.RUnlock()
 		if err != nil {
 			return err
 		}
@@ -184,43 +418,433 @@ func (r *ValkeyStore) ping() error {
 	return nil
 }
 
-// save stores the session in valkey.
-func (r *ValkeyStore) save(session *sessions.Session) error {
-	buffer := new(bytes.Buffer)
-	encoder := gob.NewEncoder(buffer)
-	if err := encoder.Encode(session.Values); err != nil {
+// save stores the session in valkey. Payloads within maxLength are written to
+// a single key; larger payloads are split into chunks written alongside a
+// part-count key, all with matching TTLs via a pipelined SETEX. Any part keys
+// left over from a previous, larger chunk count are explicitly DEL'd so
+// shrinking a session (chunked to single-key, or to fewer parts) doesn't
+// leave orphaned, unreachable fragments lingering in valkey until MaxAge.
+func (r *ValkeyStore) save(ctx context.Context, session *sessions.Session) error {
+	data, err := r.serializer.Serialize(session)
+	if err != nil {
 		return err
 	}
 
-	if len(buffer.Bytes()) > maxLength {
-		return fmt.Errorf("sessionstore: the value to store is too big")
+	data, err = compressPayload(r.compression, data)
+	if err != nil {
+		return err
 	}
 
-	return r.client.Do(context.Background(), r.client.B().Setex().Key(r.keyPrefix+session.ID).Seconds(int64(r.Options.MaxAge)).Value(buffer.String()).Build()).Error()
+	key := r.keyPrefix + session.ID
+	ttl := int64(r.Options.MaxAge)
+
+	oldCount, _ := r.client.Do(ctx, r.client.B().Get().Key(key+chunkCountSuffix).Build()).AsInt64()
+
+	if len(data) <= r.maxLength {
+		cmds := []valkey.Completed{
+			r.client.B().Setex().Key(key).Seconds(ttl).Value(string(data)).Build(),
+			r.client.B().Del().Key(key + chunkCountSuffix).Build(),
+		}
+		for _, i := range staleChunkIndices(oldCount, 0) {
+			cmds = append(cmds, r.client.B().Del().Key(partKey(key, int(i))).Build())
+		}
+
+		return firstError(r.client.DoMulti(ctx, cmds...))
+	}
+
+	parts := chunkPayload(data, r.maxLength)
+	cmds := make([]valkey.Completed, 0, len(parts)+2)
+	cmds = append(cmds, r.client.B().Del().Key(key).Build())
+	for i, part := range parts {
+		cmds = append(cmds, r.client.B().Setex().Key(partKey(key, i)).Seconds(ttl).Value(string(part)).Build())
+	}
+	for _, i := range staleChunkIndices(oldCount, int64(len(parts))) {
+		cmds = append(cmds, r.client.B().Del().Key(partKey(key, int(i))).Build())
+	}
+	cmds = append(cmds, r.client.B().Setex().Key(key+chunkCountSuffix).Seconds(ttl).Value(strconv.Itoa(len(parts))).Build())
+
+	return firstError(r.client.DoMulti(ctx, cmds...))
+}
+
+// staleChunkIndices returns the part indices in [newCount, oldCount) that
+// held data under a previous, larger chunk count and are no longer part of
+// the new shape. Returns nil when the session didn't shrink.
+func staleChunkIndices(oldCount, newCount int64) []int64 {
+	if oldCount <= newCount {
+		return nil
+	}
+
+	indices := make([]int64, 0, oldCount-newCount)
+	for i := newCount; i < oldCount; i++ {
+		indices = append(indices, i)
+	}
+
+	return indices
 }
 
 // load reads the session from valkey.
 // returns true if there is a session data in DB.
-func (r *ValkeyStore) load(session *sessions.Session) (bool, error) {
-	resp := r.client.Do(context.Background(), r.client.B().Get().Key(r.keyPrefix+session.ID).Build())
-	if err := resp.Error(); err != nil {
-		if valkey.IsValkeyNil(err) {
-			return false, nil
+//
+// When client-side caching is enabled via SetClientSideCache, single-key
+// reads are routed through the valkey-go tracking protocol via DoCache,
+// unless ctx was derived from WithoutCache.
+func (r *ValkeyStore) load(ctx context.Context, session *sessions.Session) (bool, error) {
+	key := r.keyPrefix + session.ID
+
+	countResp := r.client.Do(ctx, r.client.B().Get().Key(key+chunkCountSuffix).Build())
+
+	var data []byte
+	switch count, err := countResp.AsInt64(); {
+	case err == nil && count > 0:
+		cmds := make([]valkey.Completed, count)
+		for i := range cmds {
+			cmds[i] = r.client.B().Get().Key(partKey(key, i)).Build()
 		}
 
-		return false, err
+		buffer := new(bytes.Buffer)
+		for _, resp := range r.client.DoMulti(ctx, cmds...) {
+			part, err := resp.ToString()
+			if err != nil {
+				return false, err
+			}
+
+			buffer.WriteString(part)
+		}
+
+		data = buffer.Bytes()
+	default:
+		var resp valkey.ValkeyResult
+		if useCache(ctx, r.cacheTTL) {
+			resp = r.client.DoCache(ctx, r.client.B().Get().Key(key).Cache(), r.cacheTTL)
+		} else {
+			resp = r.client.Do(ctx, r.client.B().Get().Key(key).Build())
+		}
+
+		if err := resp.Error(); err != nil {
+			if valkey.IsValkeyNil(err) {
+				return false, nil
+			}
+
+			return false, err
+		}
+
+		str, err := resp.ToString()
+		if err != nil {
+			return false, err
+		}
+
+		data = []byte(str)
 	}
 
-	data, err := resp.ToString()
+	data, err := decompressPayload(data)
 	if err != nil {
 		return false, err
 	}
 
-	decoder := gob.NewDecoder(bytes.NewBuffer([]byte(data)))
-	return true, decoder.Decode(&session.Values)
+	return true, r.serializer.Deserialize(data, session)
 }
 
-// erase removes keys from valkey if MaxAge<0.
-func (r *ValkeyStore) erase(session *sessions.Session) error {
-	return r.client.Do(context.Background(), r.client.B().Del().Key(r.keyPrefix+session.ID).Build()).Error()
+// erase removes the session's keys from valkey, including every chunk part and
+// the part-count key when the session was stored chunked. Save calls it when
+// MaxAge<=0.
+func (r *ValkeyStore) erase(ctx context.Context, session *sessions.Session) error {
+	key := r.keyPrefix + session.ID
+
+	cmds := []valkey.Completed{
+		r.client.B().Del().Key(key).Build(),
+		r.client.B().Del().Key(key + chunkCountSuffix).Build(),
+	}
+
+	if count, err := r.client.Do(ctx, r.client.B().Get().Key(key+chunkCountSuffix).Build()).AsInt64(); err == nil {
+		for i := int64(0); i < count; i++ {
+			cmds = append(cmds, r.client.B().Del().Key(partKey(key, int(i))).Build())
+		}
+	}
+
+	return firstError(r.client.DoMulti(ctx, cmds...))
+}
+
+// Scan walks every session key under keyPrefix using the valkey SCAN command
+// and invokes fn with each session's id and remaining TTL. The scan is
+// cursor-based and non-blocking, passing batch as the SCAN COUNT hint for
+// each round-trip, and stops early if fn or ctx returns an error. Chunk part
+// keys are skipped and a chunked session is reported through its part-count
+// key; ids already reported are tracked so each session is reported once.
+//
+// On a Valkey Cluster, keys are partitioned across primaries by hash slot, so
+// the scan is driven per-node via the client's Nodes().
+func (r *ValkeyStore) Scan(ctx context.Context, batch int, fn func(id string, ttl time.Duration) error) error {
+	if batch <= 0 {
+		batch = 100
+	}
+
+	pattern := r.keyPrefix + "*"
+	seen := make(map[string]struct{})
+
+	for _, node := range r.client.Nodes() {
+		cursor := uint64(0)
+
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			entry, err := node.Do(ctx, node.B().Scan().Cursor(cursor).Match(pattern).Count(int64(batch)).Build()).AsScanEntry()
+			if err != nil {
+				return err
+			}
+
+			for _, key := range entry.Elements {
+				id, ttlSuffix, ok := r.sessionID(key)
+				if !ok {
+					continue
+				}
+
+				if _, ok := seen[id]; ok {
+					continue
+				}
+				seen[id] = struct{}{}
+
+				ttl, err := r.client.Do(ctx, r.client.B().Ttl().Key(r.keyPrefix+id+ttlSuffix).Build()).AsInt64()
+				if err != nil {
+					return err
+				}
+
+				if err := fn(id, time.Duration(ttl)*time.Second); err != nil {
+					return err
+				}
+			}
+
+			if entry.Cursor == 0 {
+				break
+			}
+
+			cursor = entry.Cursor
+		}
+	}
+
+	return nil
+}
+
+// DeleteByID removes the session with the given id, including any chunked parts.
+func (r *ValkeyStore) DeleteByID(ctx context.Context, id string) error {
+	return r.erase(ctx, &sessions.Session{ID: id})
+}
+
+// DeleteByFilter scans every session under keyPrefix, loading and decoding
+// each one, and deletes those for which filter returns true. It returns the
+// number of sessions deleted. Sessions are streamed via Scan and loaded one at
+// a time, so their values are never all held in memory; Scan's set of already
+// reported ids does grow with the number of sessions. The operation is safe to
+// cancel via ctx.
+func (r *ValkeyStore) DeleteByFilter(ctx context.Context, filter func(id string, values map[interface{}]interface{}) bool) (int, error) {
+	deleted := 0
+
+	err := r.Scan(ctx, 100, func(id string, ttl time.Duration) error {
+		session := &sessions.Session{ID: id}
+
+		ok, err := r.load(ctx, session)
+		if err != nil || !ok {
+			return err
+		}
+
+		if !filter(id, session.Values) {
+			return nil
+		}
+
+		if err := r.erase(ctx, session); err != nil {
+			return err
+		}
+
+		deleted++
+
+		return nil
+	})
+
+	return deleted, err
+}
+
+// sessionID extracts the session id from a valkey key, reporting ok=false for
+// chunk part keys so Scan only surfaces one entry per session. A chunked
+// session has no bare key (save DELs it), so its part-count key is the
+// representative entry: ttlSuffix is returned non-empty in that case so the
+// caller looks up the TTL on key+chunkCountSuffix instead of the bare key.
+func (r *ValkeyStore) sessionID(key string) (id string, ttlSuffix string, ok bool) {
+	rest := strings.TrimPrefix(key, r.keyPrefix)
+	if rest == key {
+		return "", "", false
+	}
+
+	if strings.Contains(rest, chunkPartSuffix) {
+		return "", "", false
+	}
+
+	if strings.HasSuffix(rest, chunkCountSuffix) {
+		return strings.TrimSuffix(rest, chunkCountSuffix), chunkCountSuffix, true
+	}
+
+	return rest, "", true
+}
+
+// RotateID generates a new session ID, copies the session's valkey payload
+// (including any chunked parts) from the old key(s) to the new one while
+// preserving the remaining TTL, deletes the old key(s), and updates
+// session.ID so the next Save issues a cookie for the new key. This is the
+// usual mitigation for session fixation after a privilege change. Safe for
+// concurrent use, since each call only touches the keys of its own session.
+func (r *ValkeyStore) RotateID(ctx context.Context, session *sessions.Session) error {
+	oldID := session.ID
+	if oldID == "" {
+		return fmt.Errorf("sessionstore: cannot rotate a session with no ID")
+	}
+
+	newID := strings.TrimRight(
+		base32.StdEncoding.EncodeToString(securecookie.GenerateRandomKey(randomKeyLength)),
+		"=",
+	)
+
+	oldKey := r.keyPrefix + oldID
+	newKey := r.keyPrefix + newID
+
+	if pttl, err := r.client.Do(ctx, r.client.B().Pttl().Key(oldKey).Build()).AsInt64(); err == nil && keyExists(pttl) {
+		if err := r.copyKeyWithTTL(ctx, oldKey, newKey, pttl); err != nil {
+			return err
+		}
+	}
+
+	if count, err := r.client.Do(ctx, r.client.B().Get().Key(oldKey+chunkCountSuffix).Build()).AsInt64(); err == nil {
+		partsPTTL, err := r.client.Do(ctx, r.client.B().Pttl().Key(oldKey+chunkCountSuffix).Build()).AsInt64()
+		if err != nil {
+			return err
+		}
+
+		if err := r.copyKeyWithTTL(ctx, oldKey+chunkCountSuffix, newKey+chunkCountSuffix, partsPTTL); err != nil {
+			return err
+		}
+
+		for i := int64(0); i < count; i++ {
+			if err := r.copyKeyWithTTL(ctx, partKey(oldKey, int(i)), partKey(newKey, int(i)), partsPTTL); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := r.erase(ctx, &sessions.Session{ID: oldID}); err != nil {
+		return err
+	}
+
+	session.ID = newID
+
+	return nil
+}
+
+// keyExists reports whether a PTTL result indicates the key exists, per the
+// PTTL/TTL command contract: -2 means the key is missing, -1 means it exists
+// with no expiry, and any non-negative value is its remaining TTL in ms.
+func keyExists(pttl int64) bool {
+	return pttl != -2
+}
+
+// copyKeyWithTTL copies src's value to dst via GET+SET, leaving src in place
+// for the caller to delete, and restores the
+// remaining TTL (in milliseconds, as returned by PTTL) on dst. COPY isn't an
+// option here: src and dst are an old and new session key that only share a
+// hash slot by chance, and the command builder rejects a multi-key command
+// across slots regardless of client mode. A src that no longer exists (it
+// expired between the caller's existence check and this call) is treated as
+// a no-op rather than an error.
+func (r *ValkeyStore) copyKeyWithTTL(ctx context.Context, src, dst string, pttl int64) error {
+	value, err := r.client.Do(ctx, r.client.B().Get().Key(src).Build()).ToString()
+	if err != nil {
+		if valkey.IsValkeyNil(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	set := r.client.B().Set().Key(dst).Value(value)
+	if pttl > 0 {
+		return r.client.Do(ctx, set.PxMilliseconds(pttl).Build()).Error()
+	}
+
+	return r.client.Do(ctx, set.Build()).Error()
+}
+
+// RotateKeys prepends newPairs as new securecookie codecs ahead of the
+// existing ones, so newly issued cookies are signed with the new keys while
+// cookies already signed with the old keys still validate, mirroring
+// gorilla/sessions' documented key-rotation approach. Safe for concurrent use.
+func (r *ValkeyStore) RotateKeys(newPairs ...[]byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Codecs = append(securecookie.CodecsFromPairs(newPairs...), r.Codecs...)
+}
+
+// firstError returns the first error among a batch of pipelined responses, if any.
+func firstError(responses []valkey.ValkeyResult) error {
+	for _, resp := range responses {
+		if err := resp.Error(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// chunkPayload splits data into parts of at most size bytes each.
+func chunkPayload(data []byte, size int) [][]byte {
+	parts := make([][]byte, 0, (len(data)+size-1)/size)
+	for len(data) > 0 {
+		n := size
+		if n > len(data) {
+			n = len(data)
+		}
+
+		parts = append(parts, data[:n])
+		data = data[n:]
+	}
+
+	return parts
+}
+
+// partKey builds the valkey key for the i-th chunk of key.
+func partKey(key string, i int) string {
+	return key + chunkPartSuffix + strconv.Itoa(i)
+}
+
+// compressPayload compresses data with gzip when compression is CompressionGzip.
+func compressPayload(compression CompressionType, data []byte) ([]byte, error) {
+	if compression != CompressionGzip {
+		return data, nil
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := gzip.NewWriter(buffer)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// decompressPayload gunzips data if it carries the gzip magic number, and
+// returns it unchanged otherwise so pre-existing uncompressed values still decode.
+func decompressPayload(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != gzipMagic1 || data[1] != gzipMagic2 {
+		return data, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
 }
@@ -0,0 +1,247 @@
+package valkeystore
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gorilla/sessions"
+)
+
+func TestStaleChunkIndices(t *testing.T) {
+	cases := []struct {
+		name               string
+		oldCount, newCount int64
+		want               []int64
+	}{
+		{"chunked to single key", 3, 0, []int64{0, 1, 2}},
+		{"fewer parts", 5, 2, []int64{2, 3, 4}},
+		{"more parts", 2, 5, nil},
+		{"same part count", 3, 3, nil},
+		{"no previous chunks", 0, 2, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := staleChunkIndices(c.oldCount, c.newCount)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("staleChunkIndices(%d, %d) = %v, want %v", c.oldCount, c.newCount, got, c.want)
+			}
+		})
+	}
+}
+
+func TestChunkPayload(t *testing.T) {
+	data := []byte("abcdefghij")
+
+	parts := chunkPayload(data, 3)
+	if len(parts) != 4 {
+		t.Fatalf("expected 4 parts, got %d", len(parts))
+	}
+
+	reassembled := bytes.Join(parts, nil)
+	if !bytes.Equal(reassembled, data) {
+		t.Errorf("reassembled %q, want %q", reassembled, data)
+	}
+}
+
+func TestPartKey(t *testing.T) {
+	if got, want := partKey("session-abc", 2), "session-abc:part:2"; got != want {
+		t.Errorf("partKey() = %q, want %q", got, want)
+	}
+}
+
+func TestCompressDecompressPayloadRoundTrip(t *testing.T) {
+	data := []byte("the quick brown fox jumps over the lazy dog")
+
+	compressed, err := compressPayload(CompressionGzip, data)
+	if err != nil {
+		t.Fatalf("compressPayload() error = %v", err)
+	}
+
+	if bytes.Equal(compressed, data) {
+		t.Fatalf("compressed payload equals input, compression did not run")
+	}
+
+	got, err := decompressPayload(compressed)
+	if err != nil {
+		t.Fatalf("decompressPayload() error = %v", err)
+	}
+
+	if !bytes.Equal(got, data) {
+		t.Errorf("decompressPayload() = %q, want %q", got, data)
+	}
+}
+
+func TestGobSerializerRoundTrip(t *testing.T) {
+	session := &sessions.Session{Values: map[interface{}]interface{}{
+		"user_id": 42,
+		"admin":   true,
+	}}
+
+	var serializer GobSerializer
+
+	data, err := serializer.Serialize(session)
+	if err != nil {
+		t.Fatalf("Serialize() error = %v", err)
+	}
+
+	got := &sessions.Session{}
+	if err := serializer.Deserialize(data, got); err != nil {
+		t.Fatalf("Deserialize() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Values, session.Values) {
+		t.Errorf("Deserialize() = %v, want %v", got.Values, session.Values)
+	}
+}
+
+func TestJSONSerializerRoundTrip(t *testing.T) {
+	session := &sessions.Session{Values: map[interface{}]interface{}{
+		"user_id": "42",
+		"admin":   true,
+	}}
+
+	var serializer JSONSerializer
+
+	data, err := serializer.Serialize(session)
+	if err != nil {
+		t.Fatalf("Serialize() error = %v", err)
+	}
+
+	got := &sessions.Session{}
+	if err := serializer.Deserialize(data, got); err != nil {
+		t.Fatalf("Deserialize() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Values, session.Values) {
+		t.Errorf("Deserialize() = %v, want %v", got.Values, session.Values)
+	}
+}
+
+func TestJSONSerializerRejectsNonStringKeys(t *testing.T) {
+	session := &sessions.Session{Values: map[interface{}]interface{}{
+		42: "answer",
+	}}
+
+	var serializer JSONSerializer
+
+	if _, err := serializer.Serialize(session); err == nil {
+		t.Fatal("Serialize() error = nil, want error for non-string key")
+	}
+}
+
+func TestSetSerializer(t *testing.T) {
+	store := &ValkeyStore{serializer: GobSerializer{}}
+
+	store.SetSerializer(JSONSerializer{})
+
+	if _, ok := store.serializer.(JSONSerializer); !ok {
+		t.Errorf("serializer = %T, want JSONSerializer", store.serializer)
+	}
+}
+
+func TestSessionID(t *testing.T) {
+	store := &ValkeyStore{keyPrefix: "session-"}
+
+	cases := []struct {
+		name          string
+		key           string
+		wantID        string
+		wantTTLSuffix string
+		wantOK        bool
+	}{
+		{"unchunked session key", "session-abc", "abc", "", true},
+		{"chunked session's part-count key", "session-abc:parts", "abc", chunkCountSuffix, true},
+		{"chunk part key", "session-abc:part:0", "", "", false},
+		{"key outside keyPrefix", "other-abc", "", "", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			id, ttlSuffix, ok := store.sessionID(c.key)
+			if id != c.wantID || ttlSuffix != c.wantTTLSuffix || ok != c.wantOK {
+				t.Errorf("sessionID(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					c.key, id, ttlSuffix, ok, c.wantID, c.wantTTLSuffix, c.wantOK)
+			}
+		})
+	}
+}
+
+func TestUseCache(t *testing.T) {
+	cases := []struct {
+		name     string
+		ctx      context.Context
+		cacheTTL time.Duration
+		want     bool
+	}{
+		{"caching disabled", context.Background(), 0, false},
+		{"caching enabled", context.Background(), time.Minute, true},
+		{"caching enabled but WithoutCache", WithoutCache(context.Background()), time.Minute, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := useCache(c.ctx, c.cacheTTL); got != c.want {
+				t.Errorf("useCache() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+// BenchmarkUseCache and BenchmarkUseCacheDisabled measure the per-read
+// overhead load's cache-eligibility check adds ahead of every DoCache/Do
+// dispatch. A benchmark of the DoCache round trip itself isn't possible from
+// this package: valkey-go exposes no public constructor for a successful
+// ValkeyResult or a slot-aware command Builder, so there's no way to fake a
+// Client here without a live connection.
+func BenchmarkUseCache(b *testing.B) {
+	ctx := context.Background()
+
+	for i := 0; i < b.N; i++ {
+		useCache(ctx, time.Minute)
+	}
+}
+
+func BenchmarkUseCacheDisabled(b *testing.B) {
+	ctx := context.Background()
+
+	for i := 0; i < b.N; i++ {
+		useCache(ctx, 0)
+	}
+}
+
+func TestKeyExists(t *testing.T) {
+	cases := []struct {
+		name string
+		pttl int64
+		want bool
+	}{
+		{"missing key", -2, false},
+		{"persistent key", -1, true},
+		{"key with a ttl", 1000, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := keyExists(c.pttl); got != c.want {
+				t.Errorf("keyExists(%d) = %v, want %v", c.pttl, got, c.want)
+			}
+		})
+	}
+}
+
+func TestDecompressPayloadLeavesUncompressedDataUnchanged(t *testing.T) {
+	data := []byte("plain gob or json bytes, no gzip magic number")
+
+	got, err := decompressPayload(data)
+	if err != nil {
+		t.Fatalf("decompressPayload() error = %v", err)
+	}
+
+	if !bytes.Equal(got, data) {
+		t.Errorf("decompressPayload() = %q, want unchanged %q", got, data)
+	}
+}